@@ -0,0 +1,776 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tika provides a client for interacting with a Tika Server.
+package tika
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client can make calls to a Tika Server.
+type Client struct {
+	httpClient *http.Client
+	serverURL  string
+
+	// RetryPolicy, if non-nil, controls how calls that fail with a
+	// transient error are retried. If nil, calls are attempted once.
+	RetryPolicy *RetryPolicy
+}
+
+// NewClient creates a new Client. If httpClient is nil, http.DefaultClient
+// is used.
+func NewClient(httpClient *http.Client, serverURL string) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		httpClient: httpClient,
+		serverURL:  serverURL,
+	}
+}
+
+// RetryPolicy controls how a Client retries calls to the Tika Server that
+// fail with a transient error, such as a 503 returned while Tika is still
+// warming up, or a network reset during a long OCR job.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times to attempt a call,
+	// including the first. A value <= 1 disables retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. Each subsequent retry
+	// doubles the previous delay, up to MaxDelay.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the computed delay between retries. Jitter is added
+	// after the cap, so the actual wait can exceed MaxDelay by up to
+	// Jitter*MaxDelay.
+	MaxDelay time.Duration
+
+	// Jitter is the maximum fraction, in [0, 1], of the computed delay to
+	// add at random, to avoid many clients retrying in lockstep against a
+	// recovering server.
+	Jitter float64
+
+	// Retryable reports whether a call that produced resp and err should be
+	// retried. resp is nil whenever err is non-nil. If Retryable is nil,
+	// DefaultRetryable is used.
+	Retryable func(resp *http.Response, err error) bool
+}
+
+// DefaultRetryable retries any transport-level error (resp == nil, err !=
+// nil) and any *ResponseError whose status is a 5xx other than 501 Not
+// Implemented, which Tika returns for requests it will never be able to
+// service.
+func DefaultRetryable(resp *http.Response, err error) bool {
+	if err == nil {
+		return false
+	}
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode >= http.StatusInternalServerError && respErr.StatusCode != http.StatusNotImplemented
+	}
+	return true
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts <= 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) retryable() func(*http.Response, error) bool {
+	if p != nil && p.Retryable != nil {
+		return p.Retryable
+	}
+	return DefaultRetryable
+}
+
+// delay returns how long to wait before the retry numbered attempt (0 for
+// the first retry, after the initial attempt).
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 0; i < attempt; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d += time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+	return d
+}
+
+// ResponseError is returned when the Tika Server responds with a status
+// code outside the 2xx range.
+type ResponseError struct {
+	Path       string
+	StatusCode int
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("tika: call to %q failed with status %d", e.Path, e.StatusCode)
+}
+
+// errBodyNotRetryable is returned when a RetryPolicy would retry a request
+// whose body is neither nil nor an io.Seeker, and so cannot be replayed
+// without risking sending a partially consumed or corrupt body.
+var errBodyNotRetryable = errors.New("tika: body does not support io.Seeker, cannot retry request")
+
+// noCloseReader hides a Close method from http.NewRequestWithContext. It
+// wraps a body that is both an io.Reader and an io.Closer (for example an
+// *os.File), so that the transport cannot close the underlying body once
+// the first attempt's request has been written: http.Transport closes any
+// request body implementing io.Closer as soon as it finishes sending it,
+// which would leave nothing to Seek or Read back for a retry.
+type noCloseReader struct {
+	io.Reader
+}
+
+// bodyFactory returns a function producing a fresh copy of body for each
+// attempt. A nil body always yields nil. A body implementing io.Seeker is
+// rewound to its start before every attempt after the first. Any other
+// body is handed out once; a second attempt reports errBodyNotRetryable
+// rather than risk replaying a stream that has already been consumed. The
+// returned reader never exposes a Close method, so passing it to
+// http.NewRequestWithContext cannot cause the transport to close a body
+// this function may need to replay.
+func bodyFactory(body io.Reader) func() (io.Reader, error) {
+	if body == nil {
+		return func() (io.Reader, error) { return nil, nil }
+	}
+	seeker, seekable := body.(io.Seeker)
+	_, closable := body.(io.Closer)
+	used := false
+	return func() (io.Reader, error) {
+		if !used {
+			used = true
+		} else {
+			if !seekable {
+				return nil, errBodyNotRetryable
+			}
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+		if closable {
+			return noCloseReader{body}, nil
+		}
+		return body, nil
+	}
+}
+
+// newRequest builds an HTTP request for path using method and body. ctx is
+// used to control cancellation and deadlines of the request; a nil ctx is
+// treated as context.Background().
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return http.NewRequestWithContext(ctx, method, c.serverURL+path, body)
+}
+
+// do executes req and returns its response, failing with a *ResponseError
+// if the response was not a 2xx. The caller is responsible for closing the
+// returned response's body.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, &ResponseError{Path: req.URL.Path, StatusCode: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+// call issues an HTTP request to the Tika Server at path using method and
+// body. The caller is responsible for closing the returned response's
+// body.
+func (c *Client) call(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	return c.callWithHeader(ctx, method, path, nil, body)
+}
+
+// callWithHeader is like call, but merges header into the outgoing
+// request's headers, allowing callers to set Tika's X-Tika-* and Accept
+// headers. If c.RetryPolicy is set, the call is retried, with backoff,
+// according to the policy.
+func (c *Client) callWithHeader(ctx context.Context, method, path string, header http.Header, body io.Reader) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	policy := c.RetryPolicy
+	attempts := policy.maxAttempts()
+	retryable := policy.retryable()
+	next := bodyFactory(body)
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(policy.delay(attempt - 1)):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		var b io.Reader
+		if b, err = next(); err != nil {
+			return nil, err
+		}
+		var req *http.Request
+		if req, err = c.newRequest(ctx, method, path, b); err != nil {
+			return nil, err
+		}
+		for k, vs := range header {
+			for _, v := range vs {
+				req.Header.Add(k, v)
+			}
+		}
+
+		resp, err = c.do(req)
+		if !retryable(resp, err) {
+			return resp, err
+		}
+	}
+	return resp, err
+}
+
+// bodyString reads resp's body into a string and closes it.
+func bodyString(resp *http.Response) (string, error) {
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Parse returns the content of a document using context.Background. See
+// ParseContext.
+func (c *Client) Parse(r io.Reader) (string, error) {
+	return c.ParseContext(context.Background(), r)
+}
+
+// ParseContext returns the extracted text contained in r.
+func (c *Client) ParseContext(ctx context.Context, r io.Reader) (string, error) {
+	resp, err := c.call(ctx, "PUT", "/tika", r)
+	if err != nil {
+		return "", err
+	}
+	return bodyString(resp)
+}
+
+// ParseOptions controls the Tika request headers used by ParseWithOptions,
+// which tune how a document is parsed and in what format its content is
+// returned.
+type ParseOptions struct {
+	// Accept selects the format of the extracted content, e.g. "text/plain"
+	// or "application/xhtml+xml". If empty, Tika's default is used.
+	Accept string
+
+	// OCRLanguage sets X-Tika-OCRLanguage, the Tesseract language(s) to use
+	// for OCR, e.g. "eng" or "eng+fra".
+	OCRLanguage string
+
+	// OCRStrategy sets X-Tika-PDFocrStrategy, e.g. "ocr_only" or
+	// "ocr_and_text".
+	OCRStrategy string
+
+	// Password sets X-Tika-Password, the password to use when opening an
+	// encrypted document.
+	Password string
+
+	// SkipEmbedded sets X-Tika-Skip-Embedded to "true", telling Tika not to
+	// extract the content of embedded documents.
+	SkipEmbedded bool
+}
+
+// header converts o into the request headers Tika expects. A nil o yields
+// an empty header.
+func (o *ParseOptions) header() http.Header {
+	h := make(http.Header)
+	if o == nil {
+		return h
+	}
+	if o.Accept != "" {
+		h.Set("Accept", o.Accept)
+	}
+	if o.OCRLanguage != "" {
+		h.Set("X-Tika-OCRLanguage", o.OCRLanguage)
+	}
+	if o.OCRStrategy != "" {
+		h.Set("X-Tika-PDFocrStrategy", o.OCRStrategy)
+	}
+	if o.Password != "" {
+		h.Set("X-Tika-Password", o.Password)
+	}
+	if o.SkipEmbedded {
+		h.Set("X-Tika-Skip-Embedded", "true")
+	}
+	return h
+}
+
+// ParseWithOptions returns the content of r using context.Background. See
+// ParseWithOptionsContext.
+func (c *Client) ParseWithOptions(r io.Reader, opts *ParseOptions) (string, error) {
+	return c.ParseWithOptionsContext(context.Background(), r, opts)
+}
+
+// ParseWithOptionsContext returns the content of r, using opts to control
+// Tika's extraction behavior (e.g. OCR, decryption) and the format of the
+// response.
+func (c *Client) ParseWithOptionsContext(ctx context.Context, r io.Reader, opts *ParseOptions) (string, error) {
+	resp, err := c.callWithHeader(ctx, "PUT", "/tika", opts.header(), r)
+	if err != nil {
+		return "", err
+	}
+	return bodyString(resp)
+}
+
+// ParseTo streams the extracted text of r directly to w, so that large
+// documents never need to be buffered into memory as a string. It returns
+// the number of bytes written to w.
+func (c *Client) ParseTo(ctx context.Context, r io.Reader, w io.Writer) (int64, error) {
+	header := http.Header{"Accept": []string{"text/plain"}}
+	resp, err := c.callWithHeader(ctx, "PUT", "/tika", header, r)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return io.Copy(w, resp.Body)
+}
+
+// RecursiveDoc is the metadata and content of a single document returned by
+// a recursive parse of an archive or container file.
+type RecursiveDoc map[string]interface{}
+
+// RecursiveStream is returned by ParseRecursiveStream. Docs yields one
+// RecursiveDoc per embedded document as it is decoded from the Tika
+// response, and is closed once the stream ends, whether cleanly or due to
+// an error. Callers should check Err after Docs is closed to distinguish
+// the two.
+type RecursiveStream struct {
+	Docs <-chan RecursiveDoc
+
+	mu  sync.Mutex
+	err error
+}
+
+// Err returns the error, if any, that terminated the stream. It is only
+// meaningful once Docs has been closed.
+func (s *RecursiveStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *RecursiveStream) setErr(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+// ParseRecursiveStream parses r and streams one RecursiveDoc per embedded
+// document as it is found, using a streaming JSON decoder instead of
+// buffering the full response, which is a better fit for archives with many
+// or large embedded documents.
+func (c *Client) ParseRecursiveStream(ctx context.Context, r io.Reader) *RecursiveStream {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	docs := make(chan RecursiveDoc)
+	stream := &RecursiveStream{Docs: docs}
+
+	go func() {
+		defer close(docs)
+
+		resp, err := c.call(ctx, "PUT", "/rmeta", r)
+		if err != nil {
+			stream.setErr(err)
+			return
+		}
+		defer resp.Body.Close()
+
+		dec := json.NewDecoder(resp.Body)
+		if _, err := dec.Token(); err != nil { // consume the opening '['
+			stream.setErr(err)
+			return
+		}
+		for dec.More() {
+			var doc RecursiveDoc
+			if err := dec.Decode(&doc); err != nil {
+				stream.setErr(err)
+				return
+			}
+			select {
+			case docs <- doc:
+			case <-ctx.Done():
+				stream.setErr(ctx.Err())
+				return
+			}
+		}
+		if _, err := dec.Token(); err != nil { // consume the closing ']'
+			stream.setErr(err)
+		}
+	}()
+
+	return stream
+}
+
+// ParseRecursive returns the content of r and all embedded documents, using
+// context.Background. See ParseRecursiveContext.
+func (c *Client) ParseRecursive(r io.Reader) ([]string, error) {
+	return c.ParseRecursiveContext(context.Background(), r)
+}
+
+// ParseRecursiveContext returns the content of r and all embedded documents.
+func (c *Client) ParseRecursiveContext(ctx context.Context, r io.Reader) ([]string, error) {
+	resp, err := c.call(ctx, "PUT", "/rmeta", r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var docs []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&docs); err != nil {
+		return nil, err
+	}
+
+	var contents []string
+	for _, doc := range docs {
+		content, ok := doc["X-TIKA:content"]
+		if !ok {
+			continue
+		}
+		s, ok := content.(string)
+		if !ok {
+			return nil, fmt.Errorf("tika: X-TIKA:content field was %T, want string", content)
+		}
+		contents = append(contents, s)
+	}
+	return contents, nil
+}
+
+// Meta returns the metadata of a document using context.Background. See
+// MetaContext.
+func (c *Client) Meta(r io.Reader) (string, error) {
+	return c.MetaContext(context.Background(), r)
+}
+
+// MetaContext returns the metadata of the document in r.
+func (c *Client) MetaContext(ctx context.Context, r io.Reader) (string, error) {
+	resp, err := c.call(ctx, "PUT", "/meta", r)
+	if err != nil {
+		return "", err
+	}
+	return bodyString(resp)
+}
+
+// MetaOptions controls the Tika request headers used by MetaWithOptions,
+// which tune how a document's metadata is extracted and in what format it
+// is returned.
+type MetaOptions struct {
+	// Accept selects the format of the returned metadata, e.g.
+	// "application/json" or "text/csv". If empty, Tika's default is used.
+	Accept string
+
+	// Password sets X-Tika-Password, the password to use when opening an
+	// encrypted document.
+	Password string
+
+	// SkipEmbedded sets X-Tika-Skip-Embedded to "true", telling Tika not to
+	// report metadata for embedded documents.
+	SkipEmbedded bool
+}
+
+// header converts o into the request headers Tika expects. A nil o yields
+// an empty header.
+func (o *MetaOptions) header() http.Header {
+	h := make(http.Header)
+	if o == nil {
+		return h
+	}
+	if o.Accept != "" {
+		h.Set("Accept", o.Accept)
+	}
+	if o.Password != "" {
+		h.Set("X-Tika-Password", o.Password)
+	}
+	if o.SkipEmbedded {
+		h.Set("X-Tika-Skip-Embedded", "true")
+	}
+	return h
+}
+
+// MetaWithOptions returns the metadata of a document using
+// context.Background. See MetaWithOptionsContext.
+func (c *Client) MetaWithOptions(r io.Reader, opts *MetaOptions) (string, error) {
+	return c.MetaWithOptionsContext(context.Background(), r, opts)
+}
+
+// MetaWithOptionsContext returns the metadata of the document in r, using
+// opts to control Tika's extraction behavior (e.g. decryption) and the
+// format of the response.
+func (c *Client) MetaWithOptionsContext(ctx context.Context, r io.Reader, opts *MetaOptions) (string, error) {
+	resp, err := c.callWithHeader(ctx, "PUT", "/meta", opts.header(), r)
+	if err != nil {
+		return "", err
+	}
+	return bodyString(resp)
+}
+
+// MetaField returns a single metadata field of a document using
+// context.Background. See MetaFieldContext.
+func (c *Client) MetaField(r io.Reader, field string) (string, error) {
+	return c.MetaFieldContext(context.Background(), r, field)
+}
+
+// MetaFieldContext returns the value of the given metadata field of the
+// document in r.
+func (c *Client) MetaFieldContext(ctx context.Context, r io.Reader, field string) (string, error) {
+	resp, err := c.call(ctx, "PUT", "/meta/"+field, r)
+	if err != nil {
+		return "", err
+	}
+	return bodyString(resp)
+}
+
+// Detect returns the MIME type of a document using context.Background. See
+// DetectContext.
+func (c *Client) Detect(r io.Reader) (string, error) {
+	return c.DetectContext(context.Background(), r)
+}
+
+// DetectContext returns the MIME type of the document in r.
+func (c *Client) DetectContext(ctx context.Context, r io.Reader) (string, error) {
+	resp, err := c.call(ctx, "PUT", "/detect/stream", r)
+	if err != nil {
+		return "", err
+	}
+	return bodyString(resp)
+}
+
+// Language returns the language of a document using context.Background. See
+// LanguageContext.
+func (c *Client) Language(r io.Reader) (string, error) {
+	return c.LanguageContext(context.Background(), r)
+}
+
+// LanguageContext returns the language of the document in r.
+func (c *Client) LanguageContext(ctx context.Context, r io.Reader) (string, error) {
+	resp, err := c.call(ctx, "PUT", "/language/stream", r)
+	if err != nil {
+		return "", err
+	}
+	return bodyString(resp)
+}
+
+// LanguageString returns the language of s using context.Background. See
+// LanguageStringContext.
+func (c *Client) LanguageString(s string) (string, error) {
+	return c.LanguageStringContext(context.Background(), s)
+}
+
+// LanguageStringContext returns the language of s.
+func (c *Client) LanguageStringContext(ctx context.Context, s string) (string, error) {
+	resp, err := c.call(ctx, "PUT", "/language/string", strings.NewReader(s))
+	if err != nil {
+		return "", err
+	}
+	return bodyString(resp)
+}
+
+// MetaRecursive returns the metadata of a document and all embedded
+// documents using context.Background. See MetaRecursiveContext.
+func (c *Client) MetaRecursive(r io.Reader) ([]map[string][]string, error) {
+	return c.MetaRecursiveContext(context.Background(), r)
+}
+
+// MetaRecursiveContext returns the metadata of the document in r and all of
+// its embedded documents. Each field in the response is normalized to a
+// []string, regardless of whether Tika reported it as a single string or an
+// array of strings.
+func (c *Client) MetaRecursiveContext(ctx context.Context, r io.Reader) ([]map[string][]string, error) {
+	resp, err := c.call(ctx, "PUT", "/rmeta", r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var docs []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&docs); err != nil {
+		return nil, err
+	}
+
+	var metas []map[string][]string
+	for _, doc := range docs {
+		meta := make(map[string][]string)
+		for k, v := range doc {
+			switch t := v.(type) {
+			case string:
+				meta[k] = []string{t}
+			case []interface{}:
+				var values []string
+				for _, e := range t {
+					s, ok := e.(string)
+					if !ok {
+						return nil, fmt.Errorf("tika: field %q contained a %T, want string", k, e)
+					}
+					values = append(values, s)
+				}
+				meta[k] = values
+			default:
+				return nil, fmt.Errorf("tika: field %q was %T, want string or []string", k, v)
+			}
+		}
+		metas = append(metas, meta)
+	}
+	return metas, nil
+}
+
+// Translate returns the text in r translated from src to dst using
+// translator, using context.Background. See TranslateContext.
+func (c *Client) Translate(r io.Reader, translator, src, dst string) (string, error) {
+	return c.TranslateContext(context.Background(), r, translator, src, dst)
+}
+
+// TranslateContext returns the text in r translated from src to dst using
+// translator.
+func (c *Client) TranslateContext(ctx context.Context, r io.Reader, translator, src, dst string) (string, error) {
+	resp, err := c.call(ctx, "PUT", "/translate/all/"+translator+"/"+src+"/"+dst, r)
+	if err != nil {
+		return "", err
+	}
+	return bodyString(resp)
+}
+
+// Parser represents a single Tika parser, and any parsers it is composed
+// of.
+type Parser struct {
+	Name           string   `json:"name"`
+	Decorated      bool     `json:"decorated"`
+	Composite      bool     `json:"composite"`
+	SupportedTypes []string `json:"supportedTypes"`
+	Children       []Parser `json:"children"`
+}
+
+// Parsers returns the list of parsers supported by the Tika Server, using
+// context.Background. See ParsersContext.
+func (c *Client) Parsers() (*Parser, error) {
+	return c.ParsersContext(context.Background())
+}
+
+// ParsersContext returns the list of parsers supported by the Tika Server.
+func (c *Client) ParsersContext(ctx context.Context) (*Parser, error) {
+	resp, err := c.call(ctx, "GET", "/parsers/details", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var p Parser
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Version returns the version of the Tika Server, using context.Background.
+// See VersionContext.
+func (c *Client) Version() (string, error) {
+	return c.VersionContext(context.Background())
+}
+
+// VersionContext returns the version of the Tika Server.
+func (c *Client) VersionContext(ctx context.Context) (string, error) {
+	resp, err := c.call(ctx, "GET", "/version", nil)
+	if err != nil {
+		return "", err
+	}
+	return bodyString(resp)
+}
+
+// MimeType describes a single MIME type known to the Tika Server.
+type MimeType struct {
+	Alias     []string `json:"alias"`
+	SuperType string   `json:"supertype"`
+}
+
+// MimeTypes returns the MIME types known to the Tika Server, using
+// context.Background. See MimeTypesContext.
+func (c *Client) MimeTypes() (map[string]MimeType, error) {
+	return c.MimeTypesContext(context.Background())
+}
+
+// MimeTypesContext returns the MIME types known to the Tika Server.
+func (c *Client) MimeTypesContext(ctx context.Context) (map[string]MimeType, error) {
+	resp, err := c.call(ctx, "GET", "/mime-types", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var mimes map[string]MimeType
+	if err := json.NewDecoder(resp.Body).Decode(&mimes); err != nil {
+		return nil, err
+	}
+	return mimes, nil
+}
+
+// Detector represents a single Tika detector, and any detectors it is
+// composed of.
+type Detector struct {
+	Name      string     `json:"name"`
+	Composite bool       `json:"composite"`
+	Children  []Detector `json:"children"`
+}
+
+// Detectors returns the list of detectors supported by the Tika Server,
+// using context.Background. See DetectorsContext.
+func (c *Client) Detectors() (*Detector, error) {
+	return c.DetectorsContext(context.Background())
+}
+
+// DetectorsContext returns the list of detectors supported by the Tika
+// Server.
+func (c *Client) DetectorsContext(ctx context.Context) (*Detector, error) {
+	resp, err := c.call(ctx, "GET", "/detectors", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var d Detector
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
@@ -17,12 +17,19 @@ limitations under the License.
 package tika
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // errorServer always response with http.StatusInternalServerError.
@@ -71,6 +78,63 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseWithOptions(t *testing.T) {
+	want := "test value"
+	opts := &ParseOptions{
+		Accept:       "text/plain",
+		OCRLanguage:  "eng",
+		Password:     "secret",
+		SkipEmbedded: true,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept"); got != opts.Accept {
+			t.Errorf("Accept header got %q, want %q", got, opts.Accept)
+		}
+		if got := r.Header.Get("X-Tika-OCRLanguage"); got != opts.OCRLanguage {
+			t.Errorf("X-Tika-OCRLanguage header got %q, want %q", got, opts.OCRLanguage)
+		}
+		if got := r.Header.Get("X-Tika-Password"); got != opts.Password {
+			t.Errorf("X-Tika-Password header got %q, want %q", got, opts.Password)
+		}
+		if got := r.Header.Get("X-Tika-Skip-Embedded"); got != "true" {
+			t.Errorf("X-Tika-Skip-Embedded header got %q, want %q", got, "true")
+		}
+		fmt.Fprint(w, want)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	got, err := c.ParseWithOptions(nil, opts)
+	if err != nil {
+		t.Errorf("ParseWithOptions got error %v, want nil", err)
+	}
+	if got != want {
+		t.Errorf("ParseWithOptions got %q, want %q", got, want)
+	}
+}
+
+func TestParseTo(t *testing.T) {
+	want := "test value"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept"); got != "text/plain" {
+			t.Errorf("Accept header got %q, want %q", got, "text/plain")
+		}
+		fmt.Fprint(w, want)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	var buf bytes.Buffer
+	n, err := c.ParseTo(context.Background(), nil, &buf)
+	if err != nil {
+		t.Errorf("ParseTo got error %v, want nil", err)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("ParseTo got %d bytes, want %d", n, len(want))
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("ParseTo got %q, want %q", got, want)
+	}
+}
+
 func TestParseRecursive(t *testing.T) {
 	tests := []struct {
 		response string
@@ -115,6 +179,82 @@ func TestParseRecursiveError(t *testing.T) {
 	}
 }
 
+func TestParseRecursiveStream(t *testing.T) {
+	tests := []struct {
+		response string
+		want     []string
+	}{
+		{
+			response: `[{"X-TIKA:content":"test 1"}]`,
+			want:     []string{"test 1"},
+		},
+		{
+			response: `[{"X-TIKA:content":"test 1"},{"X-TIKA:content":"test 2"}]`,
+			want:     []string{"test 1", "test 2"},
+		},
+		{
+			response: `[]`,
+		},
+	}
+	for _, test := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, test.response)
+		}))
+		defer ts.Close()
+		c := NewClient(nil, ts.URL)
+		stream := c.ParseRecursiveStream(context.Background(), nil)
+		var got []string
+		for doc := range stream.Docs {
+			content, _ := doc["X-TIKA:content"].(string)
+			got = append(got, content)
+		}
+		if err := stream.Err(); err != nil {
+			t.Errorf("ParseRecursiveStream(%q) returned an error: %v", test.response, err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseRecursiveStream(%q) got %v, want %v", test.response, got, test.want)
+		}
+	}
+}
+
+func TestParseRecursiveStreamError(t *testing.T) {
+	stream := errorClient.ParseRecursiveStream(context.Background(), nil)
+	for range stream.Docs {
+	}
+	if err := stream.Err(); err == nil {
+		t.Error("ParseRecursiveStream got no error, want an error")
+	}
+}
+
+// TestParseRecursiveStreamCancel verifies that canceling ctx unblocks the
+// stream's producer goroutine instead of leaving it stuck sending to Docs
+// forever once the caller stops draining it.
+func TestParseRecursiveStreamCancel(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"X-TIKA:content":"test 1"},{"X-TIKA:content":"test 2"}]`)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := c.ParseRecursiveStream(ctx, nil)
+
+	<-stream.Docs // consume the first document, then stop reading.
+	cancel()
+
+	// Wait for the producer to stop without receiving from Docs again, since
+	// a receive would unblock a producer that ignores ctx just as well.
+	deadline := time.Now().Add(5 * time.Second)
+	for stream.Err() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("ParseRecursiveStream did not stop after ctx was canceled; producer goroutine is stuck")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := stream.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseRecursiveStream got err %v, want %v", err, context.Canceled)
+	}
+}
+
 func TestMeta(t *testing.T) {
 	want := "test value"
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -131,6 +271,36 @@ func TestMeta(t *testing.T) {
 	}
 }
 
+func TestMetaWithOptions(t *testing.T) {
+	want := "test value"
+	opts := &MetaOptions{
+		Accept:       "application/json",
+		Password:     "secret",
+		SkipEmbedded: true,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept"); got != opts.Accept {
+			t.Errorf("Accept header got %q, want %q", got, opts.Accept)
+		}
+		if got := r.Header.Get("X-Tika-Password"); got != opts.Password {
+			t.Errorf("X-Tika-Password header got %q, want %q", got, opts.Password)
+		}
+		if got := r.Header.Get("X-Tika-Skip-Embedded"); got != "true" {
+			t.Errorf("X-Tika-Skip-Embedded header got %q, want %q", got, "true")
+		}
+		fmt.Fprint(w, want)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	got, err := c.MetaWithOptions(nil, opts)
+	if err != nil {
+		t.Errorf("MetaWithOptions got error %v, want nil", err)
+	}
+	if got != want {
+		t.Errorf("MetaWithOptions got %q, want %q", got, want)
+	}
+}
+
 func TestMetaField(t *testing.T) {
 	want := "test value"
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -612,4 +782,92 @@ func TestDetectorsError(t *testing.T) {
 	if _, err := errorClient.Detectors(); err == nil {
 		t.Errorf("Detectors got no error, want an error")
 	}
-}
\ No newline at end of file
+}
+
+func TestDefaultRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"no error", nil, false},
+		{"transport error", errors.New("connection reset"), true},
+		{"503", &ResponseError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"500", &ResponseError{StatusCode: http.StatusInternalServerError}, true},
+		{"501", &ResponseError{StatusCode: http.StatusNotImplemented}, false},
+		{"404", &ResponseError{StatusCode: http.StatusNotFound}, false},
+	}
+	for _, test := range tests {
+		if got := DefaultRetryable(nil, test.err); got != test.want {
+			t.Errorf("%s: DefaultRetryable got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCallRetries(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("server failed to read request body: %v", err)
+		}
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	// Use a real *os.File body: it is both an io.Seeker and an io.Closer,
+	// the combination that used to cause the transport to close the file
+	// out from under a retry.
+	f, err := ioutil.TempFile("", "tika-retry-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	want := "retry me"
+	if _, err := f.WriteString(want); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(nil, ts.URL)
+	c.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	resp, err := c.call(nil, "PUT", "", f)
+	if err != nil {
+		t.Fatalf("call got error %v, want nil", err)
+	}
+	got, err := bodyString(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("call got body %q, want %q", got, want)
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("server got %d requests, want 3", n)
+	}
+}
+
+func TestCallRetriesExhausted(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	c := NewClient(nil, ts.URL)
+	c.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	if _, err := c.call(nil, "PUT", "", nil); err == nil {
+		t.Error("call got no error, want an error")
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("server got %d requests, want 3", n)
+	}
+}